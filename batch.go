@@ -0,0 +1,206 @@
+package openssl
+
+// #include "shim.h"
+import "C"
+import (
+	"errors"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"unsafe"
+)
+
+// VerifyRequest is a single signature to check as part of a VerifyECDSABatch call.
+type VerifyRequest struct {
+	// PublicKey is the DER or PEM encoded EC public key to verify against. It is
+	// ignored when BatchOptions.ReusePublicKey is set.
+	PublicKey []byte
+	// Signature is the raw, fixed-width r||s concatenation for the key's curve, the
+	// same format VerifyECDSASignature takes.
+	Signature []byte
+	Data      []byte
+}
+
+// VerifyResult is the outcome of verifying one VerifyRequest.
+type VerifyResult struct {
+	OK  bool
+	Err error
+}
+
+// BatchOptions tunes VerifyECDSABatch's worker pool and caching behavior.
+type BatchOptions struct {
+	// Concurrency bounds how many signatures are verified at once. Zero means
+	// runtime.GOMAXPROCS(0).
+	Concurrency int
+
+	// ShortCircuitOnFailure stops dispatching new work once any item has failed to
+	// verify or returned an error. Items not yet started are left with the zero
+	// VerifyResult.
+	ShortCircuitOnFailure bool
+
+	// ReusePublicKey, when non-nil, is parsed once into an EVP_PKEY and reused for
+	// every item instead of each item's own PublicKey, avoiding N redundant parses
+	// when a batch is all signed by the same key.
+	ReusePublicKey []byte
+}
+
+// VerifyECDSABatch verifies many ECDSA signatures concurrently using a bounded
+// goroutine pool, one EVP_MD_CTX per worker. DER-to-EC_KEY conversions are cached so
+// repeated public keys within items are only parsed once.
+func VerifyECDSABatch(items []VerifyRequest, opts *BatchOptions) []VerifyResult {
+	if opts == nil {
+		opts = &BatchOptions{}
+	}
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = runtime.GOMAXPROCS(0)
+	}
+	if concurrency > len(items) {
+		concurrency = len(items)
+	}
+	if concurrency == 0 {
+		return nil
+	}
+
+	results := make([]VerifyResult, len(items))
+
+	cache := &pkeyCache{keys: make(map[string]*C.EVP_PKEY)}
+	defer cache.freeAll()
+
+	var sharedPkey *C.EVP_PKEY
+	if opts.ReusePublicKey != nil {
+		pkey, err := loadPublicKey(opts.ReusePublicKey)
+		if err != nil {
+			for i := range results {
+				results[i] = VerifyResult{Err: err}
+			}
+			return results
+		}
+		defer C.EVP_PKEY_free(pkey)
+		sharedPkey = pkey
+	}
+
+	indices := make(chan int)
+	var failed int32
+
+	var wg sync.WaitGroup
+	wg.Add(concurrency)
+	for w := 0; w < concurrency; w++ {
+		go func() {
+			defer wg.Done()
+			ctx := C.EVP_MD_CTX_new()
+			defer C.EVP_MD_CTX_free(ctx)
+
+			for i := range indices {
+				if opts.ShortCircuitOnFailure && atomic.LoadInt32(&failed) != 0 {
+					continue
+				}
+
+				item := items[i]
+				pkey := sharedPkey
+				if pkey == nil {
+					var err error
+					pkey, err = cache.get(item.PublicKey)
+					if err != nil {
+						results[i] = VerifyResult{Err: err}
+						atomic.StoreInt32(&failed, 1)
+						continue
+					}
+				}
+
+				ok, err := verifyWithCtx(ctx, pkey, item.Signature, item.Data)
+				results[i] = VerifyResult{OK: ok, Err: err}
+				if err != nil || !ok {
+					atomic.StoreInt32(&failed, 1)
+				}
+			}
+		}()
+	}
+
+	for i := range items {
+		indices <- i
+	}
+	close(indices)
+	wg.Wait()
+
+	return results
+}
+
+// verifyWithCtx verifies signature over data using a caller-owned, already-allocated
+// EVP_MD_CTX, so a worker goroutine can reuse it across many items. signature is the raw
+// r||s form for pkey's curve and is converted to the ASN.1 DER form EVP_DigestVerifyFinal
+// expects, mirroring VerifyECDSASignature and VerifySignature's EncodingRaw path.
+func verifyWithCtx(ctx *C.EVP_MD_CTX, pkey *C.EVP_PKEY, signature, data []byte) (bool, error) {
+	if len(signature) == 0 {
+		return false, errors.New("empty signature")
+	}
+
+	eckey := C.EVP_PKEY_get1_EC_KEY(pkey)
+	if eckey == nil {
+		return false, errors.New("failed to extract ec key")
+	}
+	defer C.EC_KEY_free(eckey)
+
+	halfLen, err := curveHalfLenForKey(eckey)
+	if err != nil {
+		return false, err
+	}
+	derSig, err := rawToASN1ECDSA(signature, halfLen)
+	if err != nil {
+		return false, err
+	}
+
+	if C.EVP_DigestVerifyInit(ctx, nil, nil, nil, pkey) != 1 {
+		return false, errors.New("unable to init digest verify")
+	}
+	if len(data) > 0 {
+		if C.EVP_DigestUpdate(ctx, unsafe.Pointer(&data[0]), C.size_t(len(data))) != 1 {
+			return false, errors.New("unable to update digest")
+		}
+	}
+	if C.EVP_DigestVerifyFinal(ctx, (*C.uchar)(&derSig[0]), C.size_t(len(derSig))) != 1 {
+		return false, nil
+	}
+	return true, nil
+}
+
+// pkeyCache parses each distinct DER/PEM public key at most once, since d2i/PEM
+// parsing round-trips through a memory BIO and is the dominant per-signature cost in
+// a large batch.
+type pkeyCache struct {
+	mu   sync.Mutex
+	keys map[string]*C.EVP_PKEY
+}
+
+func (c *pkeyCache) get(publicKey []byte) (*C.EVP_PKEY, error) {
+	k := string(publicKey)
+
+	c.mu.Lock()
+	if pkey, ok := c.keys[k]; ok {
+		c.mu.Unlock()
+		return pkey, nil
+	}
+	c.mu.Unlock()
+
+	pkey, err := loadPublicKey(publicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if existing, ok := c.keys[k]; ok {
+		C.EVP_PKEY_free(pkey)
+		return existing, nil
+	}
+	c.keys[k] = pkey
+	return pkey, nil
+}
+
+func (c *pkeyCache) freeAll() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, pkey := range c.keys {
+		C.EVP_PKEY_free(pkey)
+	}
+}
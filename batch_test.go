@@ -0,0 +1,98 @@
+package openssl
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"testing"
+)
+
+func TestVerifyECDSABatch(t *testing.T) {
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+	privDER, err := x509.MarshalPKCS8PrivateKey(priv)
+	if err != nil {
+		t.Fatalf("marshal private key: %v", err)
+	}
+	pubDER, err := x509.MarshalPKIXPublicKey(&priv.PublicKey)
+	if err != nil {
+		t.Fatalf("marshal public key: %v", err)
+	}
+	privPEM := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privDER})
+	pubPEM := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pubDER})
+
+	opts := &VerifyOptions{Hash: SHA256, Encoding: EncodingRaw}
+
+	items := make([]VerifyRequest, 5)
+	for i := range items {
+		data := []byte{'m', 's', 'g', byte('0' + i)}
+		sig, err := SignSignature(privPEM, data, opts)
+		if err != nil {
+			t.Fatalf("SignSignature: %v", err)
+		}
+		items[i] = VerifyRequest{PublicKey: pubPEM, Signature: sig, Data: data}
+	}
+	// Corrupt one signature to confirm the batch still reports per-item failure.
+	items[2].Signature[0] ^= 0xff
+
+	results := VerifyECDSABatch(items, nil)
+	if len(results) != len(items) {
+		t.Fatalf("got %d results, want %d", len(results), len(items))
+	}
+	for i, res := range results {
+		if i == 2 {
+			if res.OK {
+				t.Errorf("item %d: expected corrupted signature to fail verification", i)
+			}
+			continue
+		}
+		if res.Err != nil {
+			t.Errorf("item %d: unexpected error: %v", i, res.Err)
+		}
+		if !res.OK {
+			t.Errorf("item %d: expected signature to verify", i)
+		}
+	}
+}
+
+func TestVerifyECDSABatchReusePublicKey(t *testing.T) {
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+	privDER, err := x509.MarshalPKCS8PrivateKey(priv)
+	if err != nil {
+		t.Fatalf("marshal private key: %v", err)
+	}
+	pubDER, err := x509.MarshalPKIXPublicKey(&priv.PublicKey)
+	if err != nil {
+		t.Fatalf("marshal public key: %v", err)
+	}
+	privPEM := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privDER})
+	pubPEM := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pubDER})
+
+	opts := &VerifyOptions{Hash: SHA256, Encoding: EncodingRaw}
+	sig, err := SignSignature(privPEM, []byte("shared key payload"), opts)
+	if err != nil {
+		t.Fatalf("SignSignature: %v", err)
+	}
+
+	items := []VerifyRequest{
+		{Signature: sig, Data: []byte("shared key payload")},
+		{Signature: sig, Data: []byte("shared key payload")},
+	}
+
+	results := VerifyECDSABatch(items, &BatchOptions{ReusePublicKey: pubPEM})
+	for i, res := range results {
+		if res.Err != nil {
+			t.Errorf("item %d: unexpected error: %v", i, res.Err)
+		}
+		if !res.OK {
+			t.Errorf("item %d: expected signature to verify", i)
+		}
+	}
+}
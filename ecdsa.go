@@ -12,22 +12,10 @@ import (
 /// - Parameter signature: The ECDSA signature to verify
 /// - Parameter data: The data used to generate the signature
 /// - Returns: True if the signature was verified
+///
+/// This is the EC, raw r||s, SHA256 special case of VerifySignature and is kept for
+/// existing callers; new code should call VerifySignature directly.
 func VerifyECDSASignature(publicKey, signature, data []byte) (bool, error) {
-	ecsig := C.ECDSA_SIG_new()
-	defer C.ECDSA_SIG_free(ecsig)
-	sigData := signature
-
-	C.BN_bin2bn((*C.uchar)(&sigData[0]), 32, ecsig.r)
-	C.BN_bin2bn((*C.uchar)(&sigData[32]), 32, ecsig.s)
-
-	sigSize := C.i2d_ECDSA_SIG(ecsig, nil)
-
-	derBytes := (*C.uchar)(C.malloc(C.size_t(sigSize)))
-	defer C.free(unsafe.Pointer(derBytes))
-
-	// ignoring result, because it is the same as sigSize
-	C.i2d_ECDSA_SIG(ecsig, &derBytes)
-
 	// read EC Public Key
 	inf := C.BIO_new(C.BIO_s_mem())
 	if inf == nil {
@@ -45,6 +33,31 @@ func VerifyECDSASignature(publicKey, signature, data []byte) (bool, error) {
 	}
 	defer C.EC_KEY_free(eckey)
 
+	halfLen, err := curveHalfLenForKey(eckey)
+	if err != nil {
+		return false, err
+	}
+	if len(signature) != 2*halfLen {
+		return false, errors.New("signature has unexpected length for curve")
+	}
+
+	ecsig := C.ECDSA_SIG_new()
+	defer C.ECDSA_SIG_free(ecsig)
+	sigData := signature
+
+	C.BN_bin2bn((*C.uchar)(&sigData[0]), C.int(halfLen), ecsig.r)
+	C.BN_bin2bn((*C.uchar)(&sigData[halfLen]), C.int(halfLen), ecsig.s)
+
+	sigSize := C.i2d_ECDSA_SIG(ecsig, nil)
+
+	derBytes := (*C.uchar)(C.malloc(C.size_t(sigSize)))
+	defer C.free(unsafe.Pointer(derBytes))
+
+	// ignoring result, because it is the same as sigSize; i2d_ECDSA_SIG advances the
+	// pointer it is given, so write through a copy.
+	derOut := derBytes
+	C.i2d_ECDSA_SIG(ecsig, &derOut)
+
 	out := C.BIO_new(C.BIO_s_mem())
 	if out == nil {
 		return false, errors.New("failed allocating output buffer")
@@ -89,4 +102,4 @@ func VerifyECDSASignature(publicKey, signature, data []byte) (bool, error) {
 	}
 
 	return true, nil
-}
\ No newline at end of file
+}
@@ -0,0 +1,128 @@
+package jws
+
+// #include "shim.h"
+import "C"
+import (
+	"encoding/base64"
+	"errors"
+	"unsafe"
+)
+
+// JWK is a subset of RFC 7517 JSON Web Key fields covering the EC and RSA key types
+// produced by common JWK sets (e.g. an OIDC provider's JWKS endpoint).
+type JWK struct {
+	Kty string `json:"kty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	Y   string `json:"y,omitempty"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+}
+
+var jwkCurveNID = map[string]C.int{
+	"P-256": C.NID_X9_62_prime256v1,
+	"P-384": C.NID_secp384r1,
+	"P-521": C.NID_secp521r1,
+}
+
+func jwkBase64ToBIGNUM(field string) (*C.BIGNUM, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(field)
+	if err != nil {
+		return nil, err
+	}
+	if len(raw) == 0 {
+		return nil, errors.New("jws: empty JWK field")
+	}
+	return C.BN_bin2bn((*C.uchar)(&raw[0]), C.int(len(raw)), nil), nil
+}
+
+// PublicKeyPEM constructs an EVP_PKEY from the JWK's key material via BIGNUM building
+// and returns it PEM-encoded, suitable for passing to openssl.VerifySignature.
+func (k JWK) PublicKeyPEM() ([]byte, error) {
+	switch k.Kty {
+	case "EC":
+		return k.ecPublicKeyPEM()
+	case "RSA":
+		return k.rsaPublicKeyPEM()
+	default:
+		return nil, errors.New("jws: unsupported JWK kty " + k.Kty)
+	}
+}
+
+func (k JWK) ecPublicKeyPEM() ([]byte, error) {
+	nid, ok := jwkCurveNID[k.Crv]
+	if !ok {
+		return nil, errors.New("jws: unsupported JWK crv " + k.Crv)
+	}
+
+	x, err := jwkBase64ToBIGNUM(k.X)
+	if err != nil {
+		return nil, err
+	}
+	defer C.BN_free(x)
+	y, err := jwkBase64ToBIGNUM(k.Y)
+	if err != nil {
+		return nil, err
+	}
+	defer C.BN_free(y)
+
+	eckey := C.EC_KEY_new_by_curve_name(nid)
+	if eckey == nil {
+		return nil, errors.New("jws: failed to allocate ec key for curve " + k.Crv)
+	}
+	defer C.EC_KEY_free(eckey)
+
+	if C.EC_KEY_set_public_key_affine_coordinates(eckey, x, y) != 1 {
+		return nil, errors.New("jws: invalid ec public key coordinates")
+	}
+
+	pkey := C.EVP_PKEY_new()
+	defer C.EVP_PKEY_free(pkey)
+	if C.EVP_PKEY_set1_EC_KEY(pkey, eckey) != 1 {
+		return nil, errors.New("jws: failed to wrap ec key")
+	}
+
+	return pemWritePublicKey(pkey)
+}
+
+func (k JWK) rsaPublicKeyPEM() ([]byte, error) {
+	n, err := jwkBase64ToBIGNUM(k.N)
+	if err != nil {
+		return nil, err
+	}
+	e, err := jwkBase64ToBIGNUM(k.E)
+	if err != nil {
+		return nil, err
+	}
+
+	rsa := C.RSA_new()
+	defer C.RSA_free(rsa)
+	// RSA_set0_key takes ownership of n and e; d is nil since this is a public key.
+	if C.RSA_set0_key(rsa, n, e, nil) != 1 {
+		return nil, errors.New("jws: failed to build rsa key")
+	}
+
+	pkey := C.EVP_PKEY_new()
+	defer C.EVP_PKEY_free(pkey)
+	if C.EVP_PKEY_set1_RSA(pkey, rsa) != 1 {
+		return nil, errors.New("jws: failed to wrap rsa key")
+	}
+
+	return pemWritePublicKey(pkey)
+}
+
+func pemWritePublicKey(pkey *C.EVP_PKEY) ([]byte, error) {
+	out := C.BIO_new(C.BIO_s_mem())
+	if out == nil {
+		return nil, errors.New("jws: failed allocating output buffer")
+	}
+	defer C.BIO_free(out)
+
+	if C.PEM_write_bio_PUBKEY(out, pkey) != 1 {
+		return nil, errors.New("jws: failed to write public key")
+	}
+
+	var mem *C.BUF_MEM
+	C.BIO_ctrl(out, C.BIO_C_GET_BUF_MEM_PTR, 0, unsafe.Pointer(&mem))
+	return C.GoBytes(unsafe.Pointer(mem.data), C.int(mem.length)), nil
+}
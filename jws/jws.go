@@ -0,0 +1,140 @@
+// Package jws implements RFC 7515 JSON Web Signature compact serialization, using
+// package openssl for the underlying digest sign/verify operations.
+package jws
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+
+	"github.com/emtammaru/openssl"
+)
+
+// Header is the JWS protected header. Alg is required; Kid is optional and is carried
+// through unmodified so callers can use it to select a key from a JWK set.
+type Header struct {
+	Alg string `json:"alg"`
+	Kid string `json:"kid,omitempty"`
+}
+
+// algParams describes how a JWS "alg" value maps onto the underlying
+// openssl.VerifyOptions used to sign or verify.
+type algParams struct {
+	opts       openssl.VerifyOptions
+	saltLength int
+}
+
+var algTable = map[string]algParams{
+	"RS256": {opts: openssl.VerifyOptions{Hash: openssl.SHA256, Encoding: openssl.EncodingASN1, Padding: openssl.PaddingPKCS1}},
+	"RS384": {opts: openssl.VerifyOptions{Hash: openssl.SHA384, Encoding: openssl.EncodingASN1, Padding: openssl.PaddingPKCS1}},
+	"RS512": {opts: openssl.VerifyOptions{Hash: openssl.SHA512, Encoding: openssl.EncodingASN1, Padding: openssl.PaddingPKCS1}},
+	"PS256": {opts: openssl.VerifyOptions{Hash: openssl.SHA256, Encoding: openssl.EncodingASN1, Padding: openssl.PaddingPSS, PSSSaltLength: 32}},
+	"PS384": {opts: openssl.VerifyOptions{Hash: openssl.SHA384, Encoding: openssl.EncodingASN1, Padding: openssl.PaddingPSS, PSSSaltLength: 48}},
+	"PS512": {opts: openssl.VerifyOptions{Hash: openssl.SHA512, Encoding: openssl.EncodingASN1, Padding: openssl.PaddingPSS, PSSSaltLength: 64}},
+	"ES256": {opts: openssl.VerifyOptions{Hash: openssl.SHA256, Encoding: openssl.EncodingJWS}},
+	"ES384": {opts: openssl.VerifyOptions{Hash: openssl.SHA384, Encoding: openssl.EncodingJWS}},
+	"ES512": {opts: openssl.VerifyOptions{Hash: openssl.SHA512, Encoding: openssl.EncodingJWS}},
+	// ES256K shares ES256's digest/encoding; only the key's curve (secp256k1) differs.
+	"ES256K": {opts: openssl.VerifyOptions{Hash: openssl.SHA256, Encoding: openssl.EncodingJWS}},
+	// EdDSA (Ed25519/Ed448) has no separate digest step; openssl.VerifySignature and
+	// SignSignature detect these key types and pass OpenSSL a NULL digest, since
+	// EVP_DigestVerify*/EVP_DigestSign* do the hashing internally.
+	"EdDSA": {opts: openssl.VerifyOptions{Encoding: openssl.EncodingJWS}},
+}
+
+func algAllowed(allowedAlgs []string, alg string) bool {
+	for _, a := range allowedAlgs {
+		if a == alg {
+			return true
+		}
+	}
+	return false
+}
+
+func b64encode(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func b64decode(s string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(s)
+}
+
+// Sign produces the RFC 7515 compact serialization "header.payload.signature" for
+// payload under key, using the digest/encoding/padding implied by header.Alg.
+func Sign(header Header, payload []byte, key []byte) (string, error) {
+	params, ok := algTable[header.Alg]
+	if !ok {
+		return "", errors.New("jws: unsupported alg " + header.Alg)
+	}
+
+	headerJSON, err := json.Marshal(header)
+	if err != nil {
+		return "", err
+	}
+
+	signingInput := b64encode(headerJSON) + "." + b64encode(payload)
+
+	sig, err := openssl.SignSignature(key, []byte(signingInput), &params.opts)
+	if err != nil {
+		return "", err
+	}
+
+	return signingInput + "." + b64encode(sig), nil
+}
+
+// Verify checks token's signature under key and, if valid, returns the decoded payload.
+// allowedAlgs lists the "alg" values the caller is willing to accept for key; Verify
+// rejects any token whose header.Alg isn't in that list. Callers must pass at least one
+// algorithm rather than trusting the token's own header, since dispatching purely on an
+// attacker-controlled alg is the classic JWT "alg confusion" vulnerability.
+func Verify(token string, key []byte, allowedAlgs ...string) ([]byte, error) {
+	if len(allowedAlgs) == 0 {
+		return nil, errors.New("jws: no allowed algorithms specified")
+	}
+
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, errors.New("jws: malformed compact serialization")
+	}
+
+	headerJSON, err := b64decode(parts[0])
+	if err != nil {
+		return nil, errors.New("jws: malformed header")
+	}
+	var header Header
+	if err := json.Unmarshal(headerJSON, &header); err != nil {
+		return nil, errors.New("jws: malformed header")
+	}
+
+	if !algAllowed(allowedAlgs, header.Alg) {
+		return nil, errors.New("jws: alg " + header.Alg + " not permitted")
+	}
+
+	params, ok := algTable[header.Alg]
+	if !ok {
+		return nil, errors.New("jws: unsupported alg " + header.Alg)
+	}
+
+	sig, err := b64decode(parts[2])
+	if err != nil {
+		return nil, errors.New("jws: malformed signature")
+	}
+
+	payload, err := b64decode(parts[1])
+	if err != nil {
+		return nil, errors.New("jws: malformed payload")
+	}
+
+	signingInput := parts[0] + "." + parts[1]
+
+	ok2, err := openssl.VerifySignature(key, sig, []byte(signingInput), &params.opts)
+	if err != nil {
+		return nil, err
+	}
+	if !ok2 {
+		return nil, errors.New("jws: signature verification failed")
+	}
+
+	return payload, nil
+}
@@ -0,0 +1,105 @@
+package jws
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"strings"
+	"testing"
+)
+
+func marshalKeyPair(t *testing.T, priv, pub any) (privPEM, pubPEM []byte) {
+	t.Helper()
+
+	privDER, err := x509.MarshalPKCS8PrivateKey(priv)
+	if err != nil {
+		t.Fatalf("marshal private key: %v", err)
+	}
+	pubDER, err := x509.MarshalPKIXPublicKey(pub)
+	if err != nil {
+		t.Fatalf("marshal public key: %v", err)
+	}
+
+	privPEM = pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privDER})
+	pubPEM = pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pubDER})
+	return privPEM, pubPEM
+}
+
+func TestSignVerifyRoundTrip(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("GenerateKey (rsa): %v", err)
+	}
+	rsaPriv, rsaPub := marshalKeyPair(t, rsaKey, &rsaKey.PublicKey)
+
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey (ec): %v", err)
+	}
+	ecPriv, ecPub := marshalKeyPair(t, ecKey, &ecKey.PublicKey)
+
+	edPub, edPriv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey (ed25519): %v", err)
+	}
+	eddsaPriv, eddsaPub := marshalKeyPair(t, edPriv, edPub)
+
+	cases := []struct {
+		alg             string
+		privPEM, pubPEM []byte
+	}{
+		{"RS256", rsaPriv, rsaPub},
+		{"PS256", rsaPriv, rsaPub},
+		{"ES256", ecPriv, ecPub},
+		{"EdDSA", eddsaPriv, eddsaPub},
+	}
+
+	for _, c := range cases {
+		t.Run(c.alg, func(t *testing.T) {
+			token, err := Sign(Header{Alg: c.alg}, []byte("payload"), c.privPEM)
+			if err != nil {
+				t.Fatalf("Sign: %v", err)
+			}
+
+			payload, err := Verify(token, c.pubPEM, c.alg)
+			if err != nil {
+				t.Fatalf("Verify: %v", err)
+			}
+			if string(payload) != "payload" {
+				t.Fatalf("Verify: got payload %q, want %q", payload, "payload")
+			}
+
+			if _, err := Verify(token, c.pubPEM); err == nil {
+				t.Fatal("Verify: expected error with no allowed algs")
+			}
+			if _, err := Verify(token, c.pubPEM, "none-of-the-above"); err == nil {
+				t.Fatal("Verify: expected error when alg not in allow-list")
+			}
+		})
+	}
+}
+
+// TestVerifyEmptySignatureSegment guards against a token with an empty signature
+// segment (e.g. "header.payload.") being fed straight into openssl.VerifySignature,
+// which must return an error rather than crash.
+func TestVerifyEmptySignatureSegment(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+	rsaPriv, rsaPub := marshalKeyPair(t, rsaKey, &rsaKey.PublicKey)
+
+	token, err := Sign(Header{Alg: "RS256"}, []byte("payload"), rsaPriv)
+	if err != nil {
+		t.Fatalf("Sign: %v", err)
+	}
+
+	parts := token[:strings.LastIndex(token, ".")+1] // drop the real signature segment
+	if _, err := Verify(parts, rsaPub, "RS256"); err == nil {
+		t.Fatal("Verify: expected error for empty signature segment, got nil")
+	}
+}
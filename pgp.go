@@ -0,0 +1,169 @@
+package openssl
+
+// #include "shim.h"
+import "C"
+import (
+	"errors"
+	"unsafe"
+)
+
+// pgpCurveNID maps an OpenPGP ECC curve OID (the raw object identifier bytes as they
+// appear in a v4/v5 ECDSA/EdDSA public key packet, without the DER 06-len prefix) to
+// its OpenSSL NID. See RFC 4880bis section 9.2 for the registered curve OIDs.
+var pgpCurveNID = map[string]C.int{
+	"\x2b\x81\x04\x00\x21":                 C.NID_secp224r1,        // NIST P-224
+	"\x2a\x86\x48\xce\x3d\x03\x01\x07":     C.NID_X9_62_prime256v1, // NIST P-256
+	"\x2b\x81\x04\x00\x22":                 C.NID_secp384r1,        // NIST P-384
+	"\x2b\x81\x04\x00\x23":                 C.NID_secp521r1,        // NIST P-521
+	"\x2b\x81\x04\x00\x0a":                 C.NID_secp256k1,        // secp256k1
+	"\x2b\x24\x03\x03\x02\x08\x01\x01\x07": C.NID_brainpoolP256r1,  // brainpoolP256r1
+	"\x2b\x24\x03\x03\x02\x08\x01\x01\x0b": C.NID_brainpoolP384r1,  // brainpoolP384r1
+	"\x2b\x24\x03\x03\x02\x08\x01\x01\x0d": C.NID_brainpoolP512r1,  // brainpoolP512r1
+}
+
+// pgpEd25519OID is the Ed25519 curve OID (1.3.6.1.4.1.11591.15.1). Ed25519 keys are
+// handled separately from the EC_KEY based curves above since OpenSSL represents them
+// as a raw EVP_PKEY rather than an EC_KEY.
+const pgpEd25519OID = "\x2b\x06\x01\x04\x01\xda\x47\x0f\x01"
+
+// mpiBytes strips an OpenPGP multiprecision integer's 2-byte bit-length header and
+// returns the raw big-endian integer bytes.
+func mpiBytes(mpi []byte) ([]byte, error) {
+	if len(mpi) < 2 {
+		return nil, errors.New("mpi too short")
+	}
+	bitLen := int(mpi[0])<<8 | int(mpi[1])
+	byteLen := (bitLen + 7) / 8
+	if len(mpi[2:]) < byteLen {
+		return nil, errors.New("mpi shorter than declared length")
+	}
+	return mpi[2 : 2+byteLen], nil
+}
+
+// VerifyECDSASignatureOnCurve verifies data against an OpenPGP-style ECDSA or EdDSA
+// signature, where the public key is given as a curve OID plus an MPI-encoded point
+// (rather than a DER SubjectPublicKeyInfo) and r, s are MPI-encoded signature values.
+func VerifyECDSASignatureOnCurve(curveOID []byte, pointMPI, r, s, data []byte) (bool, error) {
+	if string(curveOID) == pgpEd25519OID {
+		return verifyEd25519OnCurve(pointMPI, r, s, data)
+	}
+
+	nid, ok := pgpCurveNID[string(curveOID)]
+	if !ok {
+		return false, errors.New("unsupported curve oid")
+	}
+
+	point, err := mpiBytes(pointMPI)
+	if err != nil {
+		return false, err
+	}
+	rBytes, err := mpiBytes(r)
+	if err != nil {
+		return false, err
+	}
+	sBytes, err := mpiBytes(s)
+	if err != nil {
+		return false, err
+	}
+
+	eckey := C.EC_KEY_new_by_curve_name(nid)
+	if eckey == nil {
+		return false, errors.New("failed to allocate ec key for curve")
+	}
+	defer C.EC_KEY_free(eckey)
+
+	group := C.EC_KEY_get0_group(eckey)
+	ecPoint := C.EC_POINT_new(group)
+	defer C.EC_POINT_free(ecPoint)
+	if C.EC_POINT_oct2point(group, ecPoint, (*C.uchar)(&point[0]), C.size_t(len(point)), nil) != 1 {
+		return false, errors.New("failed to decode public key point")
+	}
+	if C.EC_KEY_set_public_key(eckey, ecPoint) != 1 {
+		return false, errors.New("failed to set public key")
+	}
+
+	ecsig := C.ECDSA_SIG_new()
+	defer C.ECDSA_SIG_free(ecsig)
+	C.BN_bin2bn((*C.uchar)(&rBytes[0]), C.int(len(rBytes)), ecsig.r)
+	C.BN_bin2bn((*C.uchar)(&sBytes[0]), C.int(len(sBytes)), ecsig.s)
+
+	sigSize := C.i2d_ECDSA_SIG(ecsig, nil)
+	derBytes := (*C.uchar)(C.malloc(C.size_t(sigSize)))
+	defer C.free(unsafe.Pointer(derBytes))
+	// i2d_ECDSA_SIG advances the pointer it is given, so write through a copy.
+	derOut := derBytes
+	C.i2d_ECDSA_SIG(ecsig, &derOut)
+
+	pkey := C.EVP_PKEY_new()
+	defer C.EVP_PKEY_free(pkey)
+	if C.EVP_PKEY_set1_EC_KEY(pkey, eckey) != 1 {
+		return false, errors.New("failed to wrap ec key")
+	}
+
+	ctx := C.EVP_MD_CTX_new()
+	defer C.EVP_MD_CTX_free(ctx)
+	if C.EVP_DigestVerifyInit(ctx, nil, nil, nil, pkey) != 1 {
+		return false, errors.New("unable to init digest verify")
+	}
+	if len(data) > 0 {
+		if C.EVP_DigestUpdate(ctx, unsafe.Pointer(&data[0]), C.size_t(len(data))) != 1 {
+			return false, errors.New("unable to update digest")
+		}
+	}
+	if C.EVP_DigestVerifyFinal(ctx, derBytes, C.size_t(sigSize)) != 1 {
+		return false, nil
+	}
+	return true, nil
+}
+
+// verifyEd25519OnCurve verifies an OpenPGP EdDSA signature. The point MPI carries a
+// leading 0x40 prefix octet (RFC 4880bis section 13.3) ahead of the raw 32-byte
+// Ed25519 public key, and r/s are each zero-padded to 32 bytes and concatenated to
+// form the 64-byte EdDSA signature EVP_DigestVerify expects.
+func verifyEd25519OnCurve(pointMPI, r, s, data []byte) (bool, error) {
+	point, err := mpiBytes(pointMPI)
+	if err != nil {
+		return false, err
+	}
+	if len(point) != 33 || point[0] != 0x40 {
+		return false, errors.New("malformed ed25519 point encoding")
+	}
+	pub := point[1:]
+
+	rBytes, err := mpiBytes(r)
+	if err != nil {
+		return false, err
+	}
+	sBytes, err := mpiBytes(s)
+	if err != nil {
+		return false, err
+	}
+	if len(rBytes) > 32 || len(sBytes) > 32 {
+		return false, errors.New("malformed ed25519 signature component")
+	}
+
+	sig := make([]byte, 64)
+	copy(sig[32-len(rBytes):32], rBytes)
+	copy(sig[64-len(sBytes):64], sBytes)
+
+	pkey := C.EVP_PKEY_new_raw_public_key(C.NID_ED25519, nil, (*C.uchar)(&pub[0]), C.size_t(len(pub)))
+	if pkey == nil {
+		return false, errors.New("failed to build ed25519 key")
+	}
+	defer C.EVP_PKEY_free(pkey)
+
+	ctx := C.EVP_MD_CTX_new()
+	defer C.EVP_MD_CTX_free(ctx)
+	if C.EVP_DigestVerifyInit(ctx, nil, nil, nil, pkey) != 1 {
+		return false, errors.New("unable to init digest verify")
+	}
+
+	var dataPtr *C.uchar
+	if len(data) > 0 {
+		dataPtr = (*C.uchar)(&data[0])
+	}
+	if C.EVP_DigestVerify(ctx, (*C.uchar)(&sig[0]), C.size_t(len(sig)), dataPtr, C.size_t(len(data))) != 1 {
+		return false, nil
+	}
+	return true, nil
+}
@@ -0,0 +1,38 @@
+package openssl
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"testing"
+)
+
+// mpiWrap encodes b as an OpenPGP MPI: a 2-byte big-endian bit length followed by the
+// bytes themselves. It overstates the bit length to byteLen*8 rather than finding the
+// exact leading bit, which mpiBytes tolerates since it only uses it to derive byteLen.
+func mpiWrap(b []byte) []byte {
+	bitLen := len(b) * 8
+	return append([]byte{byte(bitLen >> 8), byte(bitLen)}, b...)
+}
+
+func TestVerifyECDSASignatureOnCurveEd25519(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+
+	pointMPI := mpiWrap(append([]byte{0x40}, pub...))
+
+	for _, data := range [][]byte{[]byte("hello openpgp"), {}} {
+		sig := ed25519.Sign(priv, data)
+		rMPI := mpiWrap(sig[:32])
+		sMPI := mpiWrap(sig[32:])
+
+		ok, err := VerifyECDSASignatureOnCurve([]byte(pgpEd25519OID), pointMPI, rMPI, sMPI, data)
+		if err != nil {
+			t.Fatalf("VerifyECDSASignatureOnCurve(data=%q): %v", data, err)
+		}
+		if !ok {
+			t.Fatalf("VerifyECDSASignatureOnCurve(data=%q): signature did not verify", data)
+		}
+	}
+}
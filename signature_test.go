@@ -0,0 +1,149 @@
+package openssl
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"testing"
+)
+
+func marshalKeyPair(t *testing.T, priv, pub any) (privPEM, pubPEM []byte) {
+	t.Helper()
+
+	privDER, err := x509.MarshalPKCS8PrivateKey(priv)
+	if err != nil {
+		t.Fatalf("marshal private key: %v", err)
+	}
+	pubDER, err := x509.MarshalPKIXPublicKey(pub)
+	if err != nil {
+		t.Fatalf("marshal public key: %v", err)
+	}
+
+	privPEM = pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privDER})
+	pubPEM = pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pubDER})
+	return privPEM, pubPEM
+}
+
+func roundTrip(t *testing.T, privPEM, pubPEM []byte, opts *VerifyOptions) {
+	t.Helper()
+
+	data := []byte("the quick brown fox jumps over the lazy dog")
+
+	sig, err := SignSignature(privPEM, data, opts)
+	if err != nil {
+		t.Fatalf("SignSignature: %v", err)
+	}
+
+	ok, err := VerifySignature(pubPEM, sig, data, opts)
+	if err != nil {
+		t.Fatalf("VerifySignature: %v", err)
+	}
+	if !ok {
+		t.Fatal("VerifySignature: signature did not verify")
+	}
+
+	// Tampering with the data must invalidate the signature.
+	ok, err = VerifySignature(pubPEM, sig, append(append([]byte{}, data...), 'x'), opts)
+	if err != nil {
+		t.Fatalf("VerifySignature (tampered): %v", err)
+	}
+	if ok {
+		t.Fatal("VerifySignature: tampered data verified")
+	}
+}
+
+func TestVerifySignatureECDSA(t *testing.T) {
+	curves := []struct {
+		name  string
+		curve elliptic.Curve
+	}{
+		{"P-256", elliptic.P256()},
+		{"P-384", elliptic.P384()},
+		{"P-521", elliptic.P521()},
+	}
+	encodings := []SignatureEncoding{EncodingASN1, EncodingRaw}
+
+	for _, c := range curves {
+		for _, enc := range encodings {
+			c, enc := c, enc
+			t.Run(c.name, func(t *testing.T) {
+				priv, err := ecdsa.GenerateKey(c.curve, rand.Reader)
+				if err != nil {
+					t.Fatalf("GenerateKey: %v", err)
+				}
+				privPEM, pubPEM := marshalKeyPair(t, priv, &priv.PublicKey)
+				roundTrip(t, privPEM, pubPEM, &VerifyOptions{Hash: SHA256, Encoding: enc})
+			})
+		}
+	}
+}
+
+func TestVerifySignatureEd25519(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+	privPEM, pubPEM := marshalKeyPair(t, priv, pub)
+	roundTrip(t, privPEM, pubPEM, &VerifyOptions{Encoding: EncodingJWS})
+}
+
+// TestVerifySignatureEd25519EmptyMessage exercises the zero-length message path
+// specifically: Ed25519/Ed448 can't use EVP_DigestUpdate, so this must go through the
+// one-shot EVP_DigestVerify/EVP_DigestSign rather than calling *Final on no input.
+func TestVerifySignatureEd25519EmptyMessage(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+	privPEM, pubPEM := marshalKeyPair(t, priv, pub)
+	opts := &VerifyOptions{Encoding: EncodingJWS}
+
+	sig, err := SignSignature(privPEM, nil, opts)
+	if err != nil {
+		t.Fatalf("SignSignature: %v", err)
+	}
+	ok, err := VerifySignature(pubPEM, sig, nil, opts)
+	if err != nil {
+		t.Fatalf("VerifySignature: %v", err)
+	}
+	if !ok {
+		t.Fatal("VerifySignature: signature over empty message did not verify")
+	}
+}
+
+func TestVerifySignatureEmptySignature(t *testing.T) {
+	priv, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+	_, pubPEM := marshalKeyPair(t, priv, &priv.PublicKey)
+
+	if _, err := VerifySignature(pubPEM, nil, []byte("data"), nil); err == nil {
+		t.Fatal("VerifySignature: expected error for empty signature, got nil")
+	}
+}
+
+func TestVerifySignatureRSA(t *testing.T) {
+	priv, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+	privPEM, pubPEM := marshalKeyPair(t, priv, &priv.PublicKey)
+
+	names := map[Hash]string{SHA256: "SHA256", SHA384: "SHA384", SHA512: "SHA512"}
+	for _, hash := range []Hash{SHA256, SHA384, SHA512} {
+		hash := hash
+		t.Run(names[hash]+"/PKCS1", func(t *testing.T) {
+			roundTrip(t, privPEM, pubPEM, &VerifyOptions{Hash: hash, Padding: PaddingPKCS1})
+		})
+		t.Run(names[hash]+"/PSS", func(t *testing.T) {
+			// PSSSaltLength and PSSMGFHash left at zero: salt length should default to
+			// the digest length (RSA_PSS_SALTLEN_DIGEST) and MGF1 hash should follow Hash.
+			roundTrip(t, privPEM, pubPEM, &VerifyOptions{Hash: hash, Padding: PaddingPSS})
+		})
+	}
+}
@@ -0,0 +1,181 @@
+package openssl
+
+// #include "shim.h"
+import "C"
+import (
+	"errors"
+	"runtime"
+	"unsafe"
+)
+
+// PublicKey is a DER or PEM encoded SubjectPublicKeyInfo, as accepted by
+// VerifySignature and NewVerifier.
+type PublicKey []byte
+
+// PrivateKey is a PEM encoded private key, as accepted by SignSignature and NewSigner.
+// DER input is not accepted.
+type PrivateKey []byte
+
+// Verifier incrementally verifies a signature over data written to it via io.Writer,
+// backed by a long-lived EVP_MD_CTX so callers can stream arbitrarily large payloads
+// without loading them into memory. A Verifier is not safe for concurrent use.
+type Verifier interface {
+	// Write feeds more of the signed data into the verifier.
+	Write(p []byte) (int, error)
+	// Verify finalizes the digest and checks sig against it, consuming everything
+	// written so far. Call Reset before verifying another signature.
+	Verify(sig []byte) (bool, error)
+	// Reset rewinds the verifier so it can be reused for another message, without
+	// the cost of reparsing the public key.
+	Reset() error
+}
+
+// Signer incrementally signs data written to it via io.Writer, backed by a long-lived
+// EVP_MD_CTX so callers can stream arbitrarily large payloads without loading them
+// into memory. A Signer is not safe for concurrent use.
+type Signer interface {
+	// Write feeds more of the data to be signed into the signer.
+	Write(p []byte) (int, error)
+	// Sign finalizes the digest and returns the signature over everything written so
+	// far. Call Reset before signing another message.
+	Sign() ([]byte, error)
+	// Reset rewinds the signer so it can be reused for another message, without the
+	// cost of reparsing the private key.
+	Reset() error
+}
+
+type evpVerifier struct {
+	ctx  *C.EVP_MD_CTX
+	pkey *C.EVP_PKEY
+	md   *C.EVP_MD
+}
+
+// NewVerifier constructs a Verifier that checks streamed data against signatures made
+// with pubKey under hash. The EVP_PKEY parsed from pubKey is kept alive and reused
+// across Reset calls, so a single Verifier can check many signatures cheaply.
+func NewVerifier(pubKey PublicKey, hash Hash) (Verifier, error) {
+	pkey, err := loadPublicKey(pubKey)
+	if err != nil {
+		return nil, err
+	}
+
+	v := &evpVerifier{
+		ctx:  C.EVP_MD_CTX_new(),
+		pkey: pkey,
+		md:   hash.evpMD(),
+	}
+	runtime.SetFinalizer(v, func(v *evpVerifier) {
+		C.EVP_MD_CTX_free(v.ctx)
+		C.EVP_PKEY_free(v.pkey)
+	})
+
+	if err := v.init(); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+func (v *evpVerifier) init() error {
+	if C.EVP_DigestVerifyInit(v.ctx, nil, v.md, nil, v.pkey) != 1 {
+		return errors.New("unable to init digest verify")
+	}
+	return nil
+}
+
+func (v *evpVerifier) Write(p []byte) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+	if C.EVP_DigestVerifyUpdate(v.ctx, unsafe.Pointer(&p[0]), C.size_t(len(p))) != 1 {
+		return 0, errors.New("unable to update digest")
+	}
+	return len(p), nil
+}
+
+func (v *evpVerifier) Verify(sig []byte) (bool, error) {
+	if len(sig) == 0 {
+		return false, errors.New("empty signature")
+	}
+	res := C.EVP_DigestVerifyFinal(v.ctx, (*C.uchar)(&sig[0]), C.size_t(len(sig)))
+	if res != 1 {
+		return false, nil
+	}
+	return true, nil
+}
+
+func (v *evpVerifier) Reset() error {
+	return v.init()
+}
+
+type evpSigner struct {
+	ctx  *C.EVP_MD_CTX
+	pkey *C.EVP_PKEY
+	md   *C.EVP_MD
+}
+
+// NewSigner constructs a Signer that produces signatures over streamed data with
+// privateKey under hash. The EVP_PKEY parsed from privateKey is kept alive and reused
+// across Reset calls, so a single Signer can sign many messages cheaply.
+func NewSigner(privateKey PrivateKey, hash Hash) (Signer, error) {
+	inf := C.BIO_new(C.BIO_s_mem())
+	if inf == nil {
+		return nil, errors.New("failed allocating input buffer")
+	}
+	defer C.BIO_free(inf)
+	if _, err := asAnyBio(inf).Write(privateKey); err != nil {
+		return nil, err
+	}
+
+	pkey := C.PEM_read_bio_PrivateKey(inf, nil, nil, nil)
+	if pkey == nil {
+		return nil, errors.New("failed to load private key")
+	}
+
+	s := &evpSigner{
+		ctx:  C.EVP_MD_CTX_new(),
+		pkey: pkey,
+		md:   hash.evpMD(),
+	}
+	runtime.SetFinalizer(s, func(s *evpSigner) {
+		C.EVP_MD_CTX_free(s.ctx)
+		C.EVP_PKEY_free(s.pkey)
+	})
+
+	if err := s.init(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *evpSigner) init() error {
+	if C.EVP_DigestSignInit(s.ctx, nil, s.md, nil, s.pkey) != 1 {
+		return errors.New("unable to init digest sign")
+	}
+	return nil
+}
+
+func (s *evpSigner) Write(p []byte) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+	if C.EVP_DigestSignUpdate(s.ctx, unsafe.Pointer(&p[0]), C.size_t(len(p))) != 1 {
+		return 0, errors.New("unable to update digest")
+	}
+	return len(p), nil
+}
+
+func (s *evpSigner) Sign() ([]byte, error) {
+	var sigLen C.size_t
+	if C.EVP_DigestSignFinal(s.ctx, nil, &sigLen) != 1 {
+		return nil, errors.New("unable to determine signature length")
+	}
+	sig := make([]byte, int(sigLen))
+	if C.EVP_DigestSignFinal(s.ctx, (*C.uchar)(&sig[0]), &sigLen) != 1 {
+		return nil, errors.New("unable to sign")
+	}
+	return sig[:int(sigLen)], nil
+}
+
+func (s *evpSigner) Reset() error {
+	return s.init()
+}
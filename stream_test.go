@@ -0,0 +1,72 @@
+package openssl
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"testing"
+)
+
+func TestStreamingVerifierSignerRoundTrip(t *testing.T) {
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+	privDER, err := x509.MarshalPKCS8PrivateKey(priv)
+	if err != nil {
+		t.Fatalf("marshal private key: %v", err)
+	}
+	pubDER, err := x509.MarshalPKIXPublicKey(&priv.PublicKey)
+	if err != nil {
+		t.Fatalf("marshal public key: %v", err)
+	}
+	privPEM := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privDER})
+	pubPEM := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pubDER})
+
+	signer, err := NewSigner(privPEM, SHA256)
+	if err != nil {
+		t.Fatalf("NewSigner: %v", err)
+	}
+	verifier, err := NewVerifier(pubPEM, SHA256)
+	if err != nil {
+		t.Fatalf("NewVerifier: %v", err)
+	}
+
+	messages := [][]byte{[]byte("first chunked message"), []byte("second message, reused context")}
+	for i, msg := range messages {
+		if i > 0 {
+			if err := signer.Reset(); err != nil {
+				t.Fatalf("Signer.Reset: %v", err)
+			}
+			if err := verifier.Reset(); err != nil {
+				t.Fatalf("Verifier.Reset: %v", err)
+			}
+		}
+
+		// Stream the message in small pieces to exercise the io.Writer path.
+		for _, b := range msg {
+			if _, err := signer.Write([]byte{b}); err != nil {
+				t.Fatalf("Signer.Write: %v", err)
+			}
+		}
+		sig, err := signer.Sign()
+		if err != nil {
+			t.Fatalf("Signer.Sign: %v", err)
+		}
+
+		for _, b := range msg {
+			if _, err := verifier.Write([]byte{b}); err != nil {
+				t.Fatalf("Verifier.Write: %v", err)
+			}
+		}
+		ok, err := verifier.Verify(sig)
+		if err != nil {
+			t.Fatalf("Verifier.Verify: %v", err)
+		}
+		if !ok {
+			t.Fatalf("message %d: signature did not verify", i)
+		}
+	}
+}